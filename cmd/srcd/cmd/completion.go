@@ -0,0 +1,149 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+	"github.com/src-d/engine-cli/components"
+	"github.com/src-d/engine-cli/drivers"
+)
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:       "completion [bash|zsh|fish|powershell]",
+	Short:     "Generate shell completion scripts",
+	Long:      `To load completions in your current shell session: source <(srcd completion bash)`,
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	Args:      cobra.ExactValidArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			err = rootCmd.GenPowerShellCompletion(os.Stdout)
+		}
+		if err != nil {
+			log.Printf("could not generate %s completion: %v", args[0], err)
+			os.Exit(1)
+		}
+	},
+}
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate documentation for srcd",
+}
+
+var docsManDir string
+
+// docsManCmd represents the docs man command
+var docsManCmd = &cobra.Command{
+	Use:   "man",
+	Short: "Generate man pages for srcd",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := os.MkdirAll(docsManDir, 0755); err != nil {
+			log.Printf("could not create %s: %v", docsManDir, err)
+			os.Exit(1)
+		}
+
+		header := &doc.GenManHeader{
+			Title:   "SRCD",
+			Section: "1",
+		}
+		if err := doc.GenManTree(rootCmd, header, docsManDir); err != nil {
+			log.Printf("could not generate man pages: %v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var docsMarkdownDir string
+
+// docsMarkdownCmd represents the docs markdown command
+var docsMarkdownCmd = &cobra.Command{
+	Use:   "markdown",
+	Short: "Generate markdown reference docs for srcd",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := os.MkdirAll(docsMarkdownDir, 0755); err != nil {
+			log.Printf("could not create %s: %v", docsMarkdownDir, err)
+			os.Exit(1)
+		}
+
+		if err := doc.GenMarkdownTree(rootCmd, docsMarkdownDir); err != nil {
+			log.Printf("could not generate markdown docs: %v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// completeComponentNames offers installed and available component names as
+// completions, e.g. for `components install` and `components uninstall`.
+func completeComponentNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := components.List(context.Background())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLanguages offers the language names srcd knows how to parse, e.g.
+// for `parse uast --lang`, taken from the installed bblfsh drivers.
+func completeLanguages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ds, err := drivers.List(context.Background())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	langs := make([]string, len(ds))
+	for i, d := range ds {
+		langs[i] = d.Language
+	}
+	return langs, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeWorkdir offers directories as completions for `init`.
+func completeWorkdir(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsManCmd)
+	docsCmd.AddCommand(docsMarkdownCmd)
+
+	docsManCmd.Flags().StringVar(&docsManDir, "dir", "./man", "directory to write man pages to")
+	docsMarkdownCmd.Flags().StringVar(&docsMarkdownDir, "dir", "./docs", "directory to write markdown docs to")
+
+	componentsInstallCmd.ValidArgsFunction = completeComponentNames
+	componentsUninstallCmd.ValidArgsFunction = completeComponentNames
+	parseUastCmd.RegisterFlagCompletionFunc("lang", completeLanguages)
+	initCmd.ValidArgsFunction = completeWorkdir
+}
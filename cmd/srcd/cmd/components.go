@@ -17,8 +17,10 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/src-d/engine-cli/components"
@@ -40,32 +42,157 @@ var componentsListCmd = &cobra.Command{
 			log.Printf("could not list images: %v", err)
 			os.Exit(1)
 		}
-		for _, img := range imgs {
-			fmt.Println(img)
-		}
+
+		writeOutput(imgs, func(w io.Writer, v interface{}) error {
+			for _, img := range v.([]string) {
+				fmt.Fprintln(w, img)
+			}
+			return nil
+		})
 	},
 }
 
 // componentsCmd represents the components install command
 var componentsInstallCmd = &cobra.Command{
-	Use:   "install",
+	Use:   "install name[@version]",
 	Short: "Install source{d} component",
 	Run: func(cmd *cobra.Command, args []string) {
 		for _, arg := range args {
-			log.Printf("installing %s", arg)
-			err := components.Install(context.Background(), arg)
-			if err != nil {
-				log.Printf("could not install %s: %v", arg, err)
+			name, version := splitNameVersion(arg)
+			if componentsInstallVersion != "" {
+				version = componentsInstallVersion
+			}
+
+			if version == "" {
+				log.Printf("installing %s", name)
+				if err := components.Install(context.Background(), name); err != nil {
+					log.Printf("could not install %s: %v", name, err)
+					os.Exit(1)
+				}
+				continue
+			}
+
+			log.Printf("installing %s@%s", name, version)
+			if err := components.InstallVersion(context.Background(), name, version); err != nil {
+				log.Printf("could not install %s@%s: %v", name, version, err)
 				os.Exit(1)
 			}
 		}
 	},
 }
 
+// componentsUninstallCmd represents the components uninstall command
+var componentsUninstallCmd = &cobra.Command{
+	Use:   "uninstall [name...]",
+	Short: "Uninstall source{d} components",
+	Run: func(cmd *cobra.Command, args []string) {
+		selected, _ := cmd.Flags().GetStringSlice("component")
+		names, err := resolveComponentTargets(append(args, selected...), componentsUninstallAll, false)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+
+		for _, name := range names {
+			log.Printf("uninstalling %s", name)
+			if err := components.Uninstall(context.Background(), name); err != nil {
+				log.Printf("could not uninstall %s: %v", name, err)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// componentsStatusCmd represents the components status command
+var componentsStatusCmd = &cobra.Command{
+	Use:   "status [name...]",
+	Short: "Show the installation status of source{d} components",
+	Run: func(cmd *cobra.Command, args []string) {
+		selected, _ := cmd.Flags().GetStringSlice("component")
+		// Unlike uninstall, a bare `status` with no names defaults to every
+		// component, mirroring `list` rather than requiring --all: status is
+		// read-only, so there's nothing destructive to guard against.
+		names, err := resolveComponentTargets(append(args, selected...), componentsStatusAll, true)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+
+		statuses, err := components.Status(context.Background(), names...)
+		if err != nil {
+			log.Printf("could not get component status: %v", err)
+			os.Exit(1)
+		}
+
+		writeOutput(statuses, func(w io.Writer, v interface{}) error {
+			for _, st := range v.([]components.ComponentStatus) {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", st.Name, st.ImageID, st.Version, runningState(st.Running))
+			}
+			return nil
+		})
+	},
+}
+
+// splitNameVersion splits a "name@version" argument into its parts. If no
+// version is present, version is the empty string.
+func splitNameVersion(arg string) (name, version string) {
+	parts := strings.SplitN(arg, "@", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return arg, ""
+}
+
+// resolveComponentTargets turns the --all flag and a list of positional
+// component names into the final set of component names to operate on. When
+// no names and no --all are given, defaultAll decides whether that means
+// "every component" (read-only commands like status) or an error (destructive
+// commands like uninstall, which shouldn't silently act on everything).
+func resolveComponentTargets(args []string, all, defaultAll bool) ([]string, error) {
+	if all {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("cannot use --all together with explicit component names")
+		}
+		return components.List(context.Background())
+	}
+
+	if len(args) == 0 {
+		if defaultAll {
+			return components.List(context.Background())
+		}
+		return nil, fmt.Errorf("no components given, pass names or --all")
+	}
+
+	return args, nil
+}
+
+func runningState(running bool) string {
+	if running {
+		return "running"
+	}
+	return "stopped"
+}
+
+var (
+	componentsInstallVersion string
+	componentsUninstallAll   bool
+	componentsStatusAll      bool
+)
+
 func init() {
 	rootCmd.AddCommand(componentsCmd)
 	componentsCmd.AddCommand(componentsListCmd)
 	componentsCmd.AddCommand(componentsInstallCmd)
+	componentsCmd.AddCommand(componentsUninstallCmd)
+	componentsCmd.AddCommand(componentsStatusCmd)
+
+	componentsInstallCmd.Flags().StringVar(&componentsInstallVersion, "version", "", "install this specific version instead of the latest one")
+
+	componentsUninstallCmd.Flags().BoolVar(&componentsUninstallAll, "all", false, "uninstall every installed component")
+	componentsUninstallCmd.Flags().StringSlice("component", nil, "component to uninstall, can be repeated (alias for passing names as arguments)")
+
+	componentsStatusCmd.Flags().BoolVar(&componentsStatusAll, "all", false, "show the status of every known component")
+	componentsStatusCmd.Flags().StringSlice("component", nil, "component to inspect, can be repeated (alias for passing names as arguments)")
 
 	// Here you will define your flags and configuration settings.
 
@@ -0,0 +1,52 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/src-d/engine-cli/output"
+)
+
+// outputFlag backs the global --output flag, shared by every command.
+var outputFlag string
+
+// writeOutput renders v to stdout according to the --output flag, falling
+// back to textFn for the default "text" format.
+func writeOutput(v interface{}, textFn func(io.Writer, interface{}) error) {
+	format := output.Format(outputFlag)
+	if !format.Valid() {
+		log.Printf("unknown --output format %q", outputFlag)
+		os.Exit(1)
+	}
+
+	if err := output.Write(os.Stdout, format, v, textFn); err != nil {
+		log.Printf("could not write output: %v", err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	formats := make([]string, len(output.Formats))
+	for i, f := range output.Formats {
+		formats[i] = string(f)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&outputFlag, "output", string(output.Text),
+		fmt.Sprintf("output format, one of: %v", formats))
+}
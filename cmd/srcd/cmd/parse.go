@@ -0,0 +1,183 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/src-d/engine-cli/drivers"
+	"github.com/src-d/engine-cli/uast"
+)
+
+// parseCmd represents the parse command
+var parseCmd = &cobra.Command{
+	Use:   "parse",
+	Short: "Parse files with source{d} drivers",
+}
+
+// parseDriversCmd represents the parse drivers command
+var parseDriversCmd = &cobra.Command{
+	Use:   "drivers",
+	Short: "Manage bblfsh language drivers",
+}
+
+// driverInfo is the structured record emitted by `parse drivers list`.
+type driverInfo struct {
+	Language string `json:"language"`
+	Version  string `json:"version"`
+}
+
+// parseDriversListCmd represents the parse drivers list command
+var parseDriversListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the installed bblfsh language drivers",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ds, err := drivers.List(context.Background())
+		if err != nil {
+			return fmt.Errorf("could not list drivers: %v", err)
+		}
+
+		infos := make([]driverInfo, len(ds))
+		for i, d := range ds {
+			infos[i] = driverInfo{Language: d.Language, Version: d.Version}
+		}
+
+		writeOutput(infos, func(w io.Writer, v interface{}) error {
+			tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "LANGUAGE\tVERSION")
+			fmt.Fprintln(tw, "----------\t----------")
+			for _, info := range v.([]driverInfo) {
+				fmt.Fprintf(tw, "%s\t%s\n", info.Language, info.Version)
+			}
+			return tw.Flush()
+		})
+
+		return nil
+	},
+}
+
+// parseLangCmd represents the parse lang command
+var parseLangCmd = &cobra.Command{
+	Use:   "lang <path>",
+	Short: "Detect the language of a file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lang, err := detectLang(args[0])
+		if err != nil {
+			return err
+		}
+
+		writeOutput(lang, func(w io.Writer, v interface{}) error {
+			_, err := fmt.Fprintln(w, v.(string))
+			return err
+		})
+
+		return nil
+	},
+}
+
+// parseUastCmd represents the parse uast command. The batch flags and the
+// batch (directory/--files-from) handling are added in parse_batch.go, which also
+// overrides Args and RunE with a version that can fan out across multiple
+// files, including reading the file list from stdin with no path argument.
+var parseUastCmd = &cobra.Command{
+	Use:   "uast <path>",
+	Short: "Parse a file into a UAST",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return parseUastSingle(cmd.Context(), args[0], parseUastLang, parseUastMode, parseUastQuery)
+	},
+}
+
+var (
+	parseUastLang  string
+	parseUastMode  string
+	parseUastQuery string
+)
+
+// parseUastSingle parses a single file and prints its UAST as JSON to
+// stdout. The UAST itself is already JSON, so this is the same on every
+// --output format.
+func parseUastSingle(ctx context.Context, path, lang, mode, query string) error {
+	u, err := parseUastSingleJSON(ctx, path, lang, mode, query)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(u))
+	return nil
+}
+
+// parseUastSingleJSON parses path with the bblfsh driver for lang and
+// returns its UAST as raw JSON.
+func parseUastSingleJSON(ctx context.Context, path, lang, mode, query string) (json.RawMessage, error) {
+	if lang == "" {
+		detected, err := detectLang(path)
+		if err != nil {
+			return nil, err
+		}
+		lang = detected
+	}
+
+	return uast.Parse(ctx, path, lang, mode, query)
+}
+
+// langByExtension maps file extensions to the language names bblfsh drivers
+// are keyed by.
+var langByExtension = map[string]string{
+	".py":   "python",
+	".cpp":  "c++",
+	".java": "java",
+	".js":   "javascript",
+	".bash": "shell",
+	".sh":   "shell",
+	".rb":   "ruby",
+	".go":   "go",
+	".cs":   "c#",
+	".php":  "php",
+}
+
+// detectLang guesses the language of path from its extension.
+func detectLang(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	lang, ok := langByExtension[ext]
+	if !ok {
+		return "", fmt.Errorf("could not detect language for %s", path)
+	}
+
+	return lang, nil
+}
+
+func init() {
+	rootCmd.AddCommand(parseCmd)
+
+	parseCmd.AddCommand(parseDriversCmd)
+	parseDriversCmd.AddCommand(parseDriversListCmd)
+
+	parseCmd.AddCommand(parseLangCmd)
+
+	parseCmd.AddCommand(parseUastCmd)
+	parseUastCmd.Flags().StringVar(&parseUastLang, "lang", "", "language of the file being parsed, autodetected from its extension if omitted")
+	parseUastCmd.Flags().StringVar(&parseUastMode, "mode", "semantic", "UAST transformation mode: semantic, annotated, or native")
+	parseUastCmd.Flags().StringVar(&parseUastQuery, "query", "", "XPath query to filter the resulting UAST")
+}
@@ -0,0 +1,317 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/src-d/engine-cli/output"
+)
+
+var (
+	parseUastInclude   []string
+	parseUastExclude   []string
+	parseUastRecursive bool
+	parseUastFilesFrom string
+	parseUastJobs      int
+)
+
+// uastResult is a single entry of the newline-delimited JSON stream emitted
+// by `parse uast` when run over multiple files.
+type uastResult struct {
+	Path  string          `json:"path"`
+	Lang  string          `json:"lang"`
+	Mode  string          `json:"mode"`
+	UAST  json.RawMessage `json:"uast,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// isUastBatch decides, from the raw command-line arguments alone (before
+// `--files-from`/directories are expanded into a file list), whether this
+// invocation is a batch one. Deciding from the expanded path count instead
+// would make a single-match `--include` filter on a directory silently fall
+// back to the single-file path, bypassing `--output` and NDJSON streaming.
+func isUastBatch(args []string) bool {
+	if parseUastFilesFrom != "" {
+		return true
+	}
+	if len(args) != 1 {
+		return true
+	}
+	return parseUastDir(args[0]) != ""
+}
+
+// runParseUast replaces the single-file Run of parseUastCmd with a version
+// that also accepts a directory or a `--files-from` file list, fanning the
+// work out across --jobs workers. Results stream as NDJSON only when
+// `--output json` is set; other formats are collected and rendered once the
+// batch finishes.
+func runParseUast(cmd *cobra.Command, args []string) error {
+	batch := isUastBatch(args)
+
+	paths, err := resolveUastTargets(args)
+	if err != nil {
+		return err
+	}
+
+	if !batch {
+		// Single explicit file: keep the original, non-batch behavior so
+		// existing scripts relying on `parse uast file.py` output are
+		// unaffected.
+		return parseUastOne(cmd.Context(), paths[0])
+	}
+
+	jobs := parseUastJobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	in := make(chan string)
+	out := make(chan uastResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range in {
+				out <- parseUastFile(cmd.Context(), path)
+			}
+		}()
+	}
+
+	go func() {
+		for _, path := range paths {
+			in <- path
+		}
+		close(in)
+		wg.Wait()
+		close(out)
+	}()
+
+	if output.Format(outputFlag) == output.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		for res := range out {
+			if err := enc.Encode(res); err != nil {
+				return fmt.Errorf("could not write result for %s: %v", res.Path, err)
+			}
+		}
+		return nil
+	}
+
+	// Text/YAML can't be streamed line by line the way NDJSON can, so collect
+	// every result before rendering them all at once.
+	var results []uastResult
+	for res := range out {
+		results = append(results, res)
+	}
+
+	writeOutput(results, func(w io.Writer, v interface{}) error {
+		for _, res := range v.([]uastResult) {
+			if res.Error != "" {
+				fmt.Fprintf(w, "%s\t%s\terror: %s\n", res.Path, res.Lang, res.Error)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\tok\n", res.Path, res.Lang)
+		}
+		return nil
+	})
+
+	return nil
+}
+
+// parseUastOne runs the pre-existing single-file code path.
+func parseUastOne(ctx context.Context, path string) error {
+	return parseUastSingle(ctx, path, parseUastLang, parseUastMode, parseUastQuery)
+}
+
+// parseUastFile parses a single file for the batch path, translating any
+// error into a per-file result instead of aborting the whole run. Languages
+// that are known to fail (see issue #297) surface here rather than crashing
+// the worker.
+func parseUastFile(ctx context.Context, path string) uastResult {
+	lang := parseUastLang
+	if lang == "" {
+		detected, err := detectLang(path)
+		if err != nil {
+			return uastResult{Path: path, Error: err.Error()}
+		}
+		lang = detected
+	}
+
+	u, err := parseUastSingleJSON(ctx, path, lang, parseUastMode, parseUastQuery)
+	if err != nil {
+		return uastResult{Path: path, Lang: lang, Mode: parseUastMode, Error: err.Error()}
+	}
+
+	return uastResult{Path: path, Lang: lang, Mode: parseUastMode, UAST: u}
+}
+
+// resolveUastTargets expands args (and --files-from) into the concrete list
+// of file paths to parse, applying --recursive, --include and --exclude.
+func resolveUastTargets(args []string) ([]string, error) {
+	var paths []string
+
+	if parseUastFilesFrom != "" {
+		files, err := readFilesFrom(parseUastFilesFrom)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, files...)
+	}
+
+	for _, arg := range args {
+		dir := parseUastDir(arg)
+		if dir == "" {
+			paths = append(paths, arg)
+			continue
+		}
+
+		expanded, err := walkUastDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, expanded...)
+	}
+
+	return paths, nil
+}
+
+// parseUastDir returns path if it is a directory, or "" otherwise.
+func parseUastDir(path string) string {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return ""
+	}
+	return path
+}
+
+func walkUastDir(dir string) ([]string, error) {
+	var paths []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if !parseUastRecursive && path != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		included, err := matchesUastFilters(path)
+		if err != nil {
+			return err
+		}
+		if included {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+
+	return paths, err
+}
+
+func matchesUastFilters(path string) (bool, error) {
+	base := filepath.Base(path)
+
+	if len(parseUastInclude) > 0 {
+		var matched bool
+		for _, pattern := range parseUastInclude {
+			ok, err := filepath.Match(pattern, base)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+
+	for _, pattern := range parseUastExclude {
+		ok, err := filepath.Match(pattern, base)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// readFilesFrom reads newline-separated paths from the given file, or from
+// stdin when path is "-".
+func readFilesFrom(path string) ([]string, error) {
+	f := os.Stdin
+	if path != "-" {
+		var err error
+		f, err = os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not open --files-from %s: %v", path, err)
+		}
+		defer f.Close()
+	}
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		paths = append(paths, line)
+	}
+
+	return paths, scanner.Err()
+}
+
+// validateUastArgs allows the path argument to be omitted when --files-from
+// supplies the file list instead (e.g. `--files-from -` to read from stdin).
+func validateUastArgs(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("accepts at most 1 arg, received %d", len(args))
+	}
+	if len(args) == 0 && parseUastFilesFrom == "" {
+		return fmt.Errorf("requires a path argument, or --files-from")
+	}
+	return nil
+}
+
+func init() {
+	parseUastCmd.Flags().StringSliceVar(&parseUastInclude, "include", nil, "only parse files matching this glob, can be repeated")
+	parseUastCmd.Flags().StringSliceVar(&parseUastExclude, "exclude", nil, "skip files matching this glob, can be repeated")
+	parseUastCmd.Flags().BoolVar(&parseUastRecursive, "recursive", false, "recurse into subdirectories when the argument is a directory")
+	parseUastCmd.Flags().StringVar(&parseUastFilesFrom, "files-from", "", "read the list of files to parse from this file, or - for stdin")
+	parseUastCmd.Flags().IntVar(&parseUastJobs, "jobs", 0, "number of files to parse concurrently (default: number of CPUs)")
+
+	parseUastCmd.Args = validateUastArgs
+	parseUastCmd.RunE = runParseUast
+}
@@ -7,8 +7,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"testing"
 
@@ -32,97 +32,142 @@ type testCase struct {
 	path     string
 	filename string
 	lang     string
+	// driverVersion pins the bblfsh driver used for this language instead of
+	// relying on whatever `drivers list` currently returns. Empty means "use
+	// the driver that's already installed".
+	driverVersion string
 }
 
 // Uses files from https://github.com/leachim6/hello-world
 var testCases = []testCase{
 	{
-		path:     filepath.FromSlash("testdata/hello.py"),
-		filename: "hello.py",
-		lang:     "python",
+		path:          filepath.FromSlash("testdata/hello.py"),
+		filename:      "hello.py",
+		lang:          "python",
+		driverVersion: "v2.8.0",
 	},
 	{
-		path:     filepath.FromSlash("testdata/hello-py3.py"),
-		filename: "hello-py3.py",
-		lang:     "python",
+		path:          filepath.FromSlash("testdata/hello-py3.py"),
+		filename:      "hello-py3.py",
+		lang:          "python",
+		driverVersion: "v2.8.0",
 	},
 	{
-		path:     filepath.FromSlash("testdata/hello.cpp"),
-		filename: "hello.cpp",
-		lang:     "c++",
+		path:          filepath.FromSlash("testdata/hello.cpp"),
+		filename:      "hello.cpp",
+		lang:          "c++",
+		driverVersion: "v1.1.0",
 	},
 	{
-		path:     filepath.FromSlash("testdata/hello.java"),
-		filename: "hello.java",
-		lang:     "java",
+		path:          filepath.FromSlash("testdata/hello.java"),
+		filename:      "hello.java",
+		lang:          "java",
+		driverVersion: "v2.5.0",
 	},
 	{
-		path:     filepath.FromSlash("testdata/hello.js"),
-		filename: "hello.js",
-		lang:     "javascript",
+		path:          filepath.FromSlash("testdata/hello.js"),
+		filename:      "hello.js",
+		lang:          "javascript",
+		driverVersion: "v2.6.0",
 	},
 	{
-		path:     filepath.FromSlash("testdata/hello.bash"),
-		filename: "hello.bash",
-		lang:     "shell",
+		path:          filepath.FromSlash("testdata/hello.bash"),
+		filename:      "hello.bash",
+		lang:          "shell",
+		driverVersion: "v2.4.0",
 	},
 	{
-		path:     filepath.FromSlash("testdata/hello.rb"),
-		filename: "hello.rb",
-		lang:     "ruby",
+		path:          filepath.FromSlash("testdata/hello.rb"),
+		filename:      "hello.rb",
+		lang:          "ruby",
+		driverVersion: "v2.9.0",
 	},
 	{
-		path:     filepath.FromSlash("testdata/hello.go"),
-		filename: "hello.go",
-		lang:     "go",
+		path:          filepath.FromSlash("testdata/hello.go"),
+		filename:      "hello.go",
+		lang:          "go",
+		driverVersion: "v2.5.0",
 	},
 	{
-		path:     filepath.FromSlash("testdata/hello.cs"),
-		filename: "hello.cs",
-		lang:     "c#",
+		path:          filepath.FromSlash("testdata/hello.cs"),
+		filename:      "hello.cs",
+		lang:          "c#",
+		driverVersion: "v1.4.0",
 	},
 	{
-		path:     filepath.FromSlash("testdata/hello.php"),
-		filename: "hello.php",
-		lang:     "php",
+		path:          filepath.FromSlash("testdata/hello.php"),
+		filename:      "hello.php",
+		lang:          "php",
+		driverVersion: "v2.7.0",
 	},
 }
 
 func (s *ParseTestSuite) SetupTest() {
+	for _, tc := range testCases {
+		if tc.driverVersion == "" {
+			continue
+		}
+
+		arg := fmt.Sprintf("%s@%s", tc.lang, tc.driverVersion)
+		out, err := s.RunCommand(context.TODO(), "components", "install", arg)
+		s.Require().NoError(err, out.String())
+	}
 }
 
 func (s *ParseTestSuite) TearDownTest() {
 	s.RunStop(context.Background())
 }
 
-func (s *ParseTestSuite) TestDriversList() {
+// TestStopNoErrorLogs checks that a clean `srcd stop` doesn't log anything
+// above warning severity for any component, using the structured logging
+// helpers instead of scraping stdout/stderr text.
+func (s *ParseTestSuite) TestStopNoErrorLogs() {
 	require := s.Require()
 
-	out, err := s.RunCommand(context.TODO(), "parse", "drivers", "list")
-	outStr := out.String()
+	_, messages, err := s.RunCommandStructuredLogs(context.TODO(), "stop")
+	require.NoError(err)
 
+	s.AssertNoLogAbove(messages, "warning")
+	s.AssertNoLogAbove(s.LogsByComponent(messages, "srcd"), "warning")
+}
+
+func (s *ParseTestSuite) TestCompletionBash() {
+	require := s.Require()
+
+	out, err := s.RunCommand(context.TODO(), "completion", "bash")
+	outStr := out.String()
 	require.NoError(err, outStr)
+	require.NotEmpty(outStr)
 
-	/* Example output:
+	// Ask bash itself whether the script parses, rather than approximating
+	// syntax validity with brace-counting.
+	bashCmd := exec.CommandContext(context.TODO(), "bash", "-n")
+	bashCmd.Stdin = strings.NewReader(outStr)
+	var bashErr bytes.Buffer
+	bashCmd.Stderr = &bashErr
 
-	LANGUAGE	VERSION
-	----------	----------
-	python		v2.8.0
-	cpp		v1.1.0
-	java		v2.5.0
-	javascript	v2.6.0
-	bash		v2.4.0
-	ruby		v2.9.0
-	go		v2.5.0
-	csharp		v1.4.0
-	php		v2.7.0
-	*/
+	err = bashCmd.Run()
+	require.NoError(err, "generated completion script is not valid bash: %s", bashErr.String())
+}
+
+func (s *ParseTestSuite) TestDriversList() {
+	require := s.Require()
 
-	// Simple checks to see if it's the table, and contains a known driver
-	expected := regexp.MustCompile(`LANGUAGE\s+VERSION`)
-	require.Regexp(expected, outStr)
-	expected = regexp.MustCompile(`javascript\s+v\S+`)
-	require.Regexp(expected, outStr)
+	var drivers []struct {
+		Language string `json:"language"`
+		Version  string `json:"version"`
+	}
+	err := s.RunCommandJSON(context.TODO(), &drivers, "parse", "drivers", "list")
+	require.NoError(err)
+
+	var found bool
+	for _, d := range drivers {
+		if d.Language == "javascript" {
+			found = true
+			require.NotEmpty(d.Version)
+		}
+	}
+	require.True(found, "expected a javascript driver in %+v", drivers)
 }
 
 func (s *ParseTestSuite) TestLang() {
@@ -219,4 +264,61 @@ func (s *ParseTestSuite) TestUast() {
 			})
 		}
 	}
-}
\ No newline at end of file
+}
+
+// TestUastBatch points `parse uast` at the whole testdata directory and
+// checks it streams back exactly one NDJSON result per file, with the
+// correct language classification. Languages known to be broken (see issue
+// #297) must show up as a per-file error in the stream instead of aborting
+// the batch.
+func (s *ParseTestSuite) TestUastBatch() {
+	require := s.Require()
+
+	out, err := s.runCommandStdout(context.TODO(), "parse", "uast", "testdata", "--recursive", "--output", "json")
+	require.NoError(err, out.String())
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	require.Len(lines, len(testCases), out.String())
+
+	results := make(map[string]uastResult, len(lines))
+	for _, line := range lines {
+		var res uastResult
+		require.NoError(json.Unmarshal([]byte(line), &res), line)
+		results[filepath.Base(res.Path)] = res
+	}
+
+	for _, tc := range testCases {
+		res, ok := results[tc.filename]
+		require.True(ok, "missing result for %s", tc.filename)
+		require.Equal(tc.lang, res.Lang, tc.filename)
+
+		if tc.lang == "c++" || tc.lang == "shell" || tc.lang == "c#" {
+			require.NotEmpty(res.Error, "expected %s to surface a per-file error (#297)", tc.filename)
+			continue
+		}
+
+		require.Empty(res.Error, tc.filename)
+		require.NotEmpty(res.UAST, tc.filename)
+	}
+}
+
+// TestUastFilesFromStdin checks that `--files-from -` works with no path
+// argument, reading the file list from stdin instead.
+func (s *ParseTestSuite) TestUastFilesFromStdin() {
+	require := s.Require()
+
+	var stdin strings.Builder
+	for _, tc := range testCases {
+		fmt.Fprintln(&stdin, tc.path)
+	}
+
+	command := s.CommandContext(context.TODO(), "parse", "uast", "--files-from", "-", "--output", "json")
+	command.Stdin = strings.NewReader(stdin.String())
+	var out bytes.Buffer
+	command.Stdout = &out
+
+	require.NoError(command.Run(), out.String())
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	require.Len(lines, len(testCases), out.String())
+}
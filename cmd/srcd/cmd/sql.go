@@ -0,0 +1,74 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/src-d/engine-cli/sqlquery"
+)
+
+// sqlResult is the structured record emitted by `sql` for JSON/YAML output.
+type sqlResult struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// sqlCmd represents the sql command
+var sqlCmd = &cobra.Command{
+	Use:   "sql <query>",
+	Short: "Run a SQL query against the gitbase server",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		res, err := sqlquery.Run(context.Background(), args[0])
+		if err != nil {
+			return fmt.Errorf("could not run query: %v", err)
+		}
+
+		result := sqlResult{Columns: res.Columns, Rows: res.Rows}
+
+		writeOutput(result, func(w io.Writer, v interface{}) error {
+			r := v.(sqlResult)
+
+			tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, tabwriterRow(r.Columns))
+			for _, row := range r.Rows {
+				fmt.Fprintln(tw, tabwriterRow(row))
+			}
+			return tw.Flush()
+		})
+
+		return nil
+	},
+}
+
+func tabwriterRow(cols []string) string {
+	row := ""
+	for i, col := range cols {
+		if i > 0 {
+			row += "\t"
+		}
+		row += col
+	}
+	return row
+}
+
+func init() {
+	rootCmd.AddCommand(sqlCmd)
+}
@@ -0,0 +1,75 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package output provides a small formatter that lets srcd commands emit
+// either human-oriented text or structured JSON/YAML records, selected by
+// the global --output flag.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Format is one of the output formats srcd commands support.
+type Format string
+
+const (
+	// Text renders human-oriented output. It's the default.
+	Text Format = "text"
+	// JSON renders records as indented JSON.
+	JSON Format = "json"
+	// YAML renders records as YAML.
+	YAML Format = "yaml"
+)
+
+// Formats lists every valid Format, in the order they should be presented
+// to users (e.g. in flag help text).
+var Formats = []Format{Text, JSON, YAML}
+
+// Valid reports whether f is one of the supported formats.
+func (f Format) Valid() bool {
+	switch f {
+	case Text, JSON, YAML:
+		return true
+	default:
+		return false
+	}
+}
+
+// Write renders v to w according to format. When format is Text, textFn is
+// called to perform the existing human-oriented rendering; it's ignored for
+// the structured formats, where v is marshaled directly.
+func Write(w io.Writer, format Format, v interface{}, textFn func(io.Writer, interface{}) error) error {
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case YAML:
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("could not marshal output as yaml: %v", err)
+		}
+		_, err = w.Write(b)
+		return err
+	case Text, "":
+		return textFn(w, v)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}